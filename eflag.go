@@ -1,6 +1,31 @@
 // Package eflag provides an extended flag package with enhanced features
-// including the ability to set flag values from environment variables and
+// including the ability to set flag values from environment variables,
+// from registered configuration sources (YAML, JSON, TOML, dotenv), and
 // a more convenient way to manage and parse multiple flags.
+//
+// Values are resolved with the following precedence, highest first:
+// explicit command-line flag, environment variable, registered
+// InputSources in registration order, then the flag's default.
+//
+// # Concurrent reads and live reload
+//
+// A long-running process can call FlagSet.ReParse, typically from a
+// FlagSet.WatchSignal handler, to pick up new environment variable values
+// without restarting. ReParse re-resolves each flag not set on the command
+// line against its environment variables and registered InputSources, in
+// the usual precedence order, and applies any newly-found value; a flag
+// whose env var or source value is removed keeps its last-resolved value,
+// it is not reset to its default. ReParse applies resolved values while
+// holding an internal write lock, so a goroutine that wraps its reads of
+// one or more flag variables in FlagSet.RLock/RUnlock is guaranteed to see
+// either the complete set of values from before the reload or the
+// complete set from after it, never a partial update. ReParse stores values
+// with ordinary, non-atomic writes, so reading any flag variable without
+// RLock while ReParse runs is a data race; a string, and a list/map type
+// such as StringList, IntList, Float64List, DurationList or StringMap, is
+// more than one machine word and can then be observed torn, even for a
+// single flag. Use FlagSet.OnChange to be
+// notified when a specific flag's resolved value changes.
 package eflag
 
 import (
@@ -8,6 +33,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -15,9 +41,52 @@ import (
 type Flag struct {
 	p interface{}
 	*flag.Flag
-	Name    string // Name of the flag
-	Env     string // Environment variable associated with the flag
-	Changed bool   // Indicates whether the flag has been changed
+	Name      string          // Name of the flag
+	Envs      []string        // Environment variables associated with the flag, tried in order
+	Changed   bool            // Indicates whether the flag has been changed
+	configKey string          // Key path used to look up the flag in registered InputSources
+	validate  func(any) error // Optional validator run against the resolved value by FlagSet.Parse
+
+	// cliSet records that the flag was set on the command line, which
+	// takes precedence forever: unlike a value resolved from an
+	// environment variable or InputSource, parse() never re-resolves a
+	// cliSet flag on a later ReParse.
+	cliSet bool
+}
+
+// IsSet reports whether the flag's value was explicitly provided, either on
+// the command line, via an environment variable, or by a registered
+// InputSource (see FlagSet.RegisterSource), as opposed to retaining its
+// default. It reads f.Changed, which parse() writes under fs.mu, so a
+// caller outside of validate() (which already holds fs.mu.RLock()) should
+// wrap its own call in FlagSet.RLock/RUnlock.
+func (f *Flag) IsSet() bool {
+	return f.Changed
+}
+
+// VarOption customizes how a flag registered with FlagSet.Var is resolved.
+type VarOption func(*Flag)
+
+// WithConfigKey overrides the key path eflag uses to look up this flag in
+// registered InputSources (see FlagSet.RegisterSource). By default the key
+// path is the flag name. key may be a dotted path, e.g. "db.host", to reach
+// into nested YAML/JSON/TOML structures.
+func WithConfigKey(key string) VarOption {
+	return func(f *Flag) {
+		f.configKey = key
+	}
+}
+
+// WithValidator registers fn to run against the flag's resolved value once
+// FlagSet.Parse has finished resolving it from the command line, an
+// environment variable, or a registered InputSource. fn receives the same
+// value type passed to FlagSet.Var (or, for list/map types, the value
+// returned by their Value method) and should return a non-nil error
+// describing why the value is invalid.
+func WithValidator(fn func(any) error) VarOption {
+	return func(f *Flag) {
+		f.validate = fn
+	}
 }
 
 // newFlag creates a new Flag based on the provided parameters.
@@ -44,23 +113,37 @@ func newFlag(fs *flag.FlagSet, p interface{}, name string, value interface{}, us
 	case *StringList:
 		fs.StringVar(&p.(*StringList).p, name, value.(string), usage)
 	default:
-		fmt.Printf("invalid type: %T\n", p)
-		os.Exit(1)
+		v, ok := p.(flag.Value)
+		if !ok {
+			fmt.Printf("invalid type: %T\n", p)
+			os.Exit(1)
+		}
+		if s, ok := value.(string); ok && s != "" {
+			if err := v.Set(s); err != nil {
+				fmt.Printf("invalid default value %#v for flag %s: %v\n", s, name, err)
+				os.Exit(1)
+			}
+		}
+		fs.Var(v, name, usage)
 	}
 
-	if env == "" {
-		env = MixedCapsToScreamingSnake(name)
-	} else if env == "-" {
-		// Don't read from env var
-	} else {
-		env = strings.ToUpper(env)
+	var envs []string
+	switch env {
+	case "":
+		envs = []string{MixedCapsToScreamingSnake(name)}
+	case "-":
+		// Don't read from any env var
+	default:
+		for _, e := range SplitWithComma(env) {
+			envs = append(envs, strings.ToUpper(e))
+		}
 	}
 
 	return &Flag{
 		p:    p,
 		Flag: fs.Lookup(name),
 		Name: name,
-		Env:  env,
+		Envs: envs,
 	}
 }
 
@@ -76,8 +159,21 @@ const (
 // FlagSet represents a set of flags and provides methods for parsing them.
 type FlagSet struct {
 	*flag.FlagSet
-	formal map[string]*Flag
-	prefix string // Prefix for environment variables associated with flags
+	formal          map[string]*Flag
+	prefix          string        // Prefix for environment variables associated with flags
+	sources         []InputSource // Registered configuration sources, in registration order
+	errorHandling   ErrorHandling // How Parse/ReParse report a required/validation failure
+	required        []string      // Names of flags that must be set, in the order registered
+	exclusiveGroups [][]string    // Groups of flag names of which at most one may be set
+
+	// mu guards the values of flags registered on this FlagSet while
+	// parse() applies env/source-resolved updates, so a concurrent reader
+	// holding RLock observes either the full pre-update or full
+	// post-update set of values, never a partial update. It does not
+	// protect command-line parsing, which happens once, before any
+	// goroutine can reasonably be reading the flags.
+	mu       sync.RWMutex
+	onChange map[string][]func(old, new any) // Callbacks registered with OnChange, by flag name
 }
 
 // NewFlagSet returns a new, empty flag set with the specified name and
@@ -86,7 +182,8 @@ type FlagSet struct {
 func NewFlagSet(name string, errorHandling ErrorHandling) *FlagSet {
 	flagSet := flag.NewFlagSet(name, flag.ErrorHandling(errorHandling))
 	f := &FlagSet{
-		FlagSet: flagSet,
+		FlagSet:       flagSet,
+		errorHandling: errorHandling,
 	}
 	return f
 }
@@ -106,60 +203,117 @@ func (fs *FlagSet) SetPrefix(prefix string) {
 // variable, and usage description. It is recommended to use this function
 // during the initialization phase to register flags.
 //
-// The env parameter determines the association with an environment variable:
+// The env parameter determines the association with one or more environment
+// variables:
 //   - When env is an empty string (""): The environment variable name will be derived
 //     from the flag name by converting it to uppercase and replacing any camel case with underscores.
 //     For example, if the flag name is "mixedCaps", the derived environment variable name will be "MIXED_CAPS".
 //     An optional prefix can be added to the environment variable name by using SetPrefix() function.
 //   - When env is "-": The flag will not be associated with any environment
 //     variable, and environment variable checking will be ignored.
-func (fs *FlagSet) Var(p interface{}, name string, value interface{}, usage, env string) {
+//   - Otherwise, env may be a comma-separated list of environment variable
+//     names, e.g. "DATABASE_URL,DB_URL,PG_URL". They are tried in order and
+//     the first one that is set wins, which is useful for migrating a flag
+//     to a new env var name while still honoring old ones.
+//
+// Additional behavior, such as the key path used to resolve the flag
+// against registered InputSources, can be customized with opts (see
+// WithConfigKey).
+func (fs *FlagSet) Var(p interface{}, name string, value interface{}, usage, env string, opts ...VarOption) {
 	if fs.formal == nil {
 		fs.formal = make(map[string]*Flag)
 	}
-	fs.formal[name] = newFlag(fs.FlagSet, p, name, value, usage, env)
+	f := newFlag(fs.FlagSet, p, name, value, usage, env)
+	for _, opt := range opts {
+		opt(f)
+	}
+	fs.formal[name] = f
 }
 
-// Parse parses command-line flags and sets values from environment variables.
-func (fs *FlagSet) Parse(arguments []string) {
-	fs.FlagSet.Parse(arguments)
+// Lookup returns the Flag structure of the named flag, returning nil if
+// none exists.
+func (fs *FlagSet) Lookup(name string) *Flag {
+	return fs.formal[name]
+}
+
+// RegisterSource adds an InputSource that parse() consults, in registration
+// order, for any flag not already set by a command-line argument or an
+// environment variable. See the package documentation for the full
+// precedence chain.
+func (fs *FlagSet) RegisterSource(src InputSource) {
+	fs.sources = append(fs.sources, src)
+}
+
+// Parse parses command-line flags and sets values from environment
+// variables. Once values are resolved, it checks that every flag named via
+// Required was set and runs every per-flag validator registered with
+// WithValidator and every group registered with MutuallyExclusive,
+// collecting all violations into a single error rather than stopping at
+// the first one. A non-nil error is handled according to fs's
+// ErrorHandling: returned as-is for ContinueOnError, printed followed by
+// os.Exit(2) for ExitOnError, or passed to panic for PanicOnError.
+func (fs *FlagSet) Parse(arguments []string) error {
+	if err := fs.FlagSet.Parse(arguments); err != nil {
+		return err
+	}
 
 	fs.FlagSet.Visit(func(f *flag.Flag) {
 		// Visit() visits only those flags that have been set.
-		fs.formal[f.Name].Changed = true
+		ff := fs.formal[f.Name]
+		ff.Changed = true
+		ff.cliSet = true
 	})
 
 	fs.parse()
+
+	return fs.validate()
 }
 
 // ReParse re-parses flags. This can be useful in scenarios where the
-// environment variables have changed, and you want to update the flag values.
-func (fs *FlagSet) ReParse() {
+// environment variables have changed, and you want to update the flag
+// values. Like Parse, it re-runs required/validator/mutually-exclusive
+// checks afterward.
+func (fs *FlagSet) ReParse() error {
 	fs.parse()
+
+	return fs.validate()
 }
 
-// parse sets flag values from environment variables and respects
-// the precedence of explicitly set flags over environment variables.
+// parse resolves flag values in the following precedence, highest first:
+//
+//  1. Explicitly set on the command line.
+//  2. The flag's environment variables, in order, first one set wins.
+//  3. Registered InputSources (see FlagSet.RegisterSource), in registration order.
+//  4. The flag's default value.
+//
+// A flag set on the command line keeps that value forever; every other
+// flag is re-resolved from its environment variables and InputSources each
+// time parse runs, so a later ReParse picks up changes to either.
 func (fs *FlagSet) parse() {
+	fs.mu.Lock()
+
+	before := make(map[string]any, len(fs.onChange))
+	for name := range fs.onChange {
+		if f := fs.formal[name]; f != nil {
+			before[name] = f.currentValue()
+		}
+	}
+
 	prefix := fs.prefix
 	for _, f := range fs.formal {
-		if f.Changed {
-			// Explicitly set flag has the highest precedence
+		if f.cliSet {
+			// Explicitly set on the command line has the highest
+			// precedence and is never re-resolved.
 			continue
 		}
 
-		if f.Env == "-" {
+		if fs.setFromEnv(f, prefix) {
+			f.Changed = true
 			continue
 		}
 
-		if prefix != "" && !strings.HasPrefix(f.Env, prefix) {
-			f.Env = prefix + f.Env
-		}
-		if v := os.Getenv(f.Env); v != "" {
-			if err := f.Flag.Value.Set(v); err != nil {
-				fmt.Printf("invalid value %#v for env %s: parse error\n", v, f.Env)
-				os.Exit(2)
-			}
+		if fs.setFromSources(f) {
+			f.Changed = true
 		}
 	}
 
@@ -169,6 +323,82 @@ func (fs *FlagSet) parse() {
 			f.p.(*StringList).setValue()
 		}
 	}
+
+	notices := fs.changeNotices(before)
+	fs.mu.Unlock()
+
+	fs.fireChangeNotices(notices)
+}
+
+// resolveEnvName prepends prefix to env, unless env is already prefixed.
+func resolveEnvName(env, prefix string) string {
+	if prefix != "" && !strings.HasPrefix(env, prefix) {
+		return prefix + env
+	}
+	return env
+}
+
+// setFromEnv looks f's environment variables up in order, applying the
+// first one found to be set. It reports whether a value was applied.
+func (fs *FlagSet) setFromEnv(f *Flag, prefix string) bool {
+	for _, env := range f.Envs {
+		env = resolveEnvName(env, prefix)
+		// LookupEnv, rather than a Getenv/"" check, so a variable the
+		// user exported as empty (e.g. PREFIX_FOO=) is still applied
+		// instead of being treated the same as an unset variable.
+		v, ok := os.LookupEnv(env)
+		if !ok {
+			continue
+		}
+		if err := f.Flag.Value.Set(v); err != nil {
+			fmt.Printf("invalid value %#v for env %s: parse error\n", v, env)
+			os.Exit(2)
+		}
+		return true
+	}
+	return false
+}
+
+// commaSeparatedValue is implemented by flag.Value types whose Set expects
+// a comma-joined string (see SplitWithComma): StringList, IntList,
+// Float64List, DurationList, and StringMap. setFromSources uses it to
+// decide whether to look a key up via InputSource.StringSlice, joining the
+// result with ",", rather than InputSource.String, since a source such as
+// JSON may store the value as a native list or object rather than a flat
+// string.
+type commaSeparatedValue interface {
+	commaSeparated()
+}
+
+// setFromSources looks f up in fs.sources, in registration order, and
+// applies the first match found. It reports whether a value was applied.
+func (fs *FlagSet) setFromSources(f *Flag) bool {
+	key := f.configKey
+	if key == "" {
+		key = f.Name
+	}
+
+	for _, src := range fs.sources {
+		var v string
+		var ok bool
+		if _, isList := f.p.(commaSeparatedValue); isList {
+			var list []string
+			if list, ok = src.StringSlice(key); ok {
+				v = strings.Join(list, ",")
+			}
+		} else {
+			v, ok = src.String(key)
+		}
+		if !ok {
+			continue
+		}
+		if err := f.Flag.Value.Set(v); err != nil {
+			fmt.Printf("invalid value %#v for config key %s: parse error\n", v, key)
+			os.Exit(2)
+		}
+		return true
+	}
+	return false
 }
 
 // CommandLine represents the default set of flags that are parsed
@@ -177,8 +407,8 @@ func (fs *FlagSet) parse() {
 var CommandLine = NewFlagSet(os.Args[0], ExitOnError)
 
 // Var registers a command-line flag and associates it with a variable, environment.
-func Var(p interface{}, name string, value interface{}, usage, env string) {
-	CommandLine.Var(p, name, value, usage, env)
+func Var(p interface{}, name string, value interface{}, usage, env string, opts ...VarOption) {
+	CommandLine.Var(p, name, value, usage, env, opts...)
 }
 
 // SetPrefix set environment variable prefix.
@@ -186,15 +416,20 @@ func SetPrefix(prefix string) {
 	CommandLine.SetPrefix(prefix)
 }
 
-// Parse parses all registered flags.
-func Parse() {
-	CommandLine.Parse(os.Args[1:])
+// RegisterSource adds an InputSource to CommandLine. See FlagSet.RegisterSource.
+func RegisterSource(src InputSource) {
+	CommandLine.RegisterSource(src)
+}
+
+// Parse parses all registered flags. See FlagSet.Parse.
+func Parse() error {
+	return CommandLine.Parse(os.Args[1:])
 }
 
 // ReParse re-parses all registered flags. This is useful when
 // environment variables have changed, and you want to update the flag values.
-func ReParse() {
-	CommandLine.ReParse()
+func ReParse() error {
+	return CommandLine.ReParse()
 }
 
 // Func defines a flag with the specified name and usage string.
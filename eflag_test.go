@@ -97,3 +97,126 @@ func TestParseWithoutPrefix(t *testing.T) {
 		t.Errorf("Expected testunset to be unset, but got '%v'", fl.IsSet())
 	}
 }
+
+func TestParseEmptyEnvOverride(t *testing.T) {
+	var myString string
+	var myStringList StringList
+	var myBool bool
+
+	os.Setenv("EMPTY_STRING", "")
+	os.Setenv("EMPTY_STRING_LIST", "")
+	os.Setenv("FALSE_BOOL", "false")
+
+	f := NewFlagSet("test", ExitOnError)
+	f.Var(&myString, "mystring", "default", "Description for mystring flag", "EMPTY_STRING")
+	f.Var(&myStringList, "mystringlist", "a,b", "Description for mystringlist flag", "EMPTY_STRING_LIST")
+	f.Var(&myBool, "mybool", true, "Description for mybool flag", "FALSE_BOOL")
+
+	f.Parse(nil)
+
+	if myString != "" {
+		t.Errorf("Expected myString to be empty, but got '%s'", myString)
+	}
+	if fl := f.Lookup("mystring"); !fl.IsSet() {
+		t.Error("Expected mystring to be set via an empty env var, but it's not.")
+	}
+
+	if len(myStringList.Value()) != 0 {
+		t.Errorf("Expected myStringList to be empty, but got %v", myStringList.Value())
+	}
+	if fl := f.Lookup("mystringlist"); !fl.IsSet() {
+		t.Error("Expected mystringlist to be set via an empty env var, but it's not.")
+	}
+
+	if myBool {
+		t.Error("Expected myBool to be false, but it's true.")
+	}
+	if fl := f.Lookup("mybool"); !fl.IsSet() {
+		t.Error("Expected mybool to be set via the FALSE_BOOL env var, but it's not.")
+	}
+}
+
+func TestReParseEmptyEnvOverrideClearsStringList(t *testing.T) {
+	var myStringList StringList
+
+	os.Setenv("MY_LIST", "a,b,c")
+
+	f := NewFlagSet("test", ExitOnError)
+	f.Var(&myStringList, "mylist", "", "Description for mylist flag", "MY_LIST")
+
+	f.Parse(nil)
+	if !reflect.DeepEqual(myStringList.Value(), []string{"a", "b", "c"}) {
+		t.Fatalf("Expected myStringList to be %v, but got %v", []string{"a", "b", "c"}, myStringList.Value())
+	}
+
+	os.Setenv("MY_LIST", "")
+	f.ReParse()
+
+	if len(myStringList.Value()) != 0 {
+		t.Errorf("Expected myStringList to be cleared after MY_LIST was set to empty, but got %v", myStringList.Value())
+	}
+	if fl := f.Lookup("mylist"); !fl.IsSet() {
+		t.Error("Expected mylist to be set via an empty env var, but it's not.")
+	}
+}
+
+func TestParseMultiEnvFallback(t *testing.T) {
+	var dbURL string
+	var firstWins string
+
+	os.Unsetenv("DATABASE_URL")
+	os.Unsetenv("DB_URL")
+	os.Setenv("PG_URL", "postgres://fallback")
+	os.Setenv("FIRST", "first")
+	os.Setenv("SECOND", "second")
+
+	f := NewFlagSet("test", ExitOnError)
+	f.Var(&dbURL, "dburl", "default", "Description for dburl flag", "DATABASE_URL,DB_URL,PG_URL")
+	f.Var(&firstWins, "firstwins", "default", "Description for firstwins flag", "FIRST,SECOND")
+
+	f.Parse(nil)
+
+	if dbURL != "postgres://fallback" {
+		t.Errorf("Expected dbURL to be 'postgres://fallback', but got '%s'", dbURL)
+	}
+	if fl := f.Lookup("dburl"); !reflect.DeepEqual(fl.Envs, []string{"DATABASE_URL", "DB_URL", "PG_URL"}) {
+		t.Errorf("Expected dburl Envs to be %v, but got %v", []string{"DATABASE_URL", "DB_URL", "PG_URL"}, fl.Envs)
+	}
+
+	if firstWins != "first" {
+		t.Errorf("Expected firstWins to be 'first', but got '%s'", firstWins)
+	}
+}
+
+func TestParseWithGenericValue(t *testing.T) {
+	var ports IntList
+	env := NewEnumString([]string{"dev", "staging", "prod"}, "dev")
+
+	os.Setenv("PORTS", "8080,8443")
+
+	f := NewFlagSet("test", ExitOnError)
+	f.Var(&ports, "ports", "", "Description for ports flag", "PORTS")
+	f.Var(env, "env", "", "Description for env flag", "-")
+
+	f.Parse([]string{"-env", "prod"})
+
+	if !reflect.DeepEqual(ports.Value(), []int{8080, 8443}) {
+		t.Errorf("Expected ports to be %v, but got %v", []int{8080, 8443}, ports.Value())
+	}
+
+	if env.Value() != "prod" {
+		t.Errorf("Expected env to be 'prod', but got '%s'", env.Value())
+	}
+}
+
+func TestParseContinueOnErrorReturnsCLIParseError(t *testing.T) {
+	var s string
+
+	f := NewFlagSet("test", ContinueOnError)
+	f.Var(&s, "name", "default", "Description for name flag", "-")
+
+	err := f.Parse([]string{"--unknown-flag"})
+	if err == nil {
+		t.Fatal("Parse() expected error for an unknown flag, got nil")
+	}
+}
@@ -0,0 +1,140 @@
+package eflag
+
+import (
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// OnChange registers fn to be called whenever ReParse (or Parse) resolves
+// a new value for the named flag that differs from its previous value,
+// for example after a SIGHUP-triggered reload picks up a changed
+// environment variable (see FlagSet.WatchSignal). old and new use the same
+// representation as WithValidator's validator function: the dereferenced
+// value for concrete types, or the value returned by Value()/Get() for
+// list, map, and generic flag.Value types. A flag.Value that does not
+// implement flag.Getter is represented by the pointer passed to Var, which
+// never differs from itself, so fn is not called for such a flag.
+//
+// fn runs synchronously, after the new value has been stored and fs's
+// value lock released, so it's safe for fn to call FlagSet methods such as
+// Lookup or RLock.
+func (fs *FlagSet) OnChange(name string, fn func(old, new any)) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.onChange == nil {
+		fs.onChange = make(map[string][]func(old, new any))
+	}
+	fs.onChange[name] = append(fs.onChange[name], fn)
+}
+
+// OnChange registers a change callback for the named flag on CommandLine.
+// See FlagSet.OnChange.
+func OnChange(name string, fn func(old, new any)) {
+	CommandLine.OnChange(name, fn)
+}
+
+// changeNotice pairs a flag's old and new value with the callbacks to run
+// for it, captured while fs.mu is held so they can be fired after it's
+// released.
+type changeNotice struct {
+	fns      []func(old, new any)
+	old, new any
+}
+
+// changeNotices compares before, a snapshot of the flags with registered
+// OnChange callbacks taken before this parse() ran, against their current
+// values, and returns a notice for each one that changed. It must be
+// called with fs.mu held.
+func (fs *FlagSet) changeNotices(before map[string]any) []changeNotice {
+	if len(before) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(before))
+	for name := range before {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var notices []changeNotice
+	for _, name := range names {
+		f := fs.formal[name]
+		if f == nil {
+			continue
+		}
+		oldValue := before[name]
+		newValue := f.currentValue()
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		notices = append(notices, changeNotice{
+			fns: fs.onChange[name],
+			old: oldValue,
+			new: newValue,
+		})
+	}
+	return notices
+}
+
+// fireChangeNotices runs the callbacks in notices. It must be called
+// without fs.mu held, since callbacks are free to call back into fs.
+func (fs *FlagSet) fireChangeNotices(notices []changeNotice) {
+	for _, n := range notices {
+		for _, fn := range n.fns {
+			fn(n.old, n.new)
+		}
+	}
+}
+
+// RLock acquires fs's value lock for reading flag variables from a
+// goroutine other than the one driving Parse/ReParse/WatchSignal.
+// Multiple readers may hold it at once, and holding it guarantees that
+// reads of several flags observe either the complete set of values from
+// before a reload or the complete set from after it, never a mix of the
+// two. It excludes parse() while held, so release it promptly.
+func (fs *FlagSet) RLock() {
+	fs.mu.RLock()
+}
+
+// RUnlock releases a lock acquired by RLock.
+func (fs *FlagSet) RUnlock() {
+	fs.mu.RUnlock()
+}
+
+// WatchSignal starts a goroutine that calls ReParse every time the process
+// receives sig, so a long-running process can pick up changed environment
+// variables after an operator sends a reload signal (conventionally
+// syscall.SIGHUP). It returns a stop function that ends the watch; stop is
+// safe to call more than once, including concurrently.
+func (fs *FlagSet) WatchSignal(sig os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ch:
+				fs.ReParse()
+			case <-done:
+				signal.Stop(ch)
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// WatchSignal starts a goroutine that calls ReParse on CommandLine every
+// time the process receives sig. See FlagSet.WatchSignal.
+func WatchSignal(sig os.Signal) (stop func()) {
+	return CommandLine.WatchSignal(sig)
+}
@@ -0,0 +1,116 @@
+package eflag
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestOnChangeFiresOnReParse(t *testing.T) {
+	var host string
+
+	t.Setenv("HOST", "first.example.com")
+
+	f := NewFlagSet("test", ExitOnError)
+	f.Var(&host, "host", "default", "Host usage", "")
+
+	var old, new_ any
+	calls := 0
+	f.OnChange("host", func(o, n any) {
+		calls++
+		old, new_ = o, n
+	})
+
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call after initial Parse, got %d", calls)
+	}
+	if old != "default" || new_ != "first.example.com" {
+		t.Errorf("expected change default -> first.example.com, got %v -> %v", old, new_)
+	}
+
+	t.Setenv("HOST", "second.example.com")
+	if err := f.ReParse(); err != nil {
+		t.Fatalf("ReParse() error = %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls after ReParse with a changed value, got %d", calls)
+	}
+	if old != "first.example.com" || new_ != "second.example.com" {
+		t.Errorf("expected change first.example.com -> second.example.com, got %v -> %v", old, new_)
+	}
+
+	if err := f.ReParse(); err != nil {
+		t.Fatalf("ReParse() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected no call for an unchanged ReParse, got %d total calls", calls)
+	}
+}
+
+func TestOnChangeDoesNotFireForExplicitlySetFlag(t *testing.T) {
+	var host string
+
+	f := NewFlagSet("test", ExitOnError)
+	f.Var(&host, "host", "default", "Host usage", "-")
+
+	calls := 0
+	f.OnChange("host", func(o, n any) { calls++ })
+
+	if err := f.Parse([]string{"-host", "cli.example.com"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected no call for the initial CLI-set value, got %d", calls)
+	}
+
+	if err := f.ReParse(); err != nil {
+		t.Fatalf("ReParse() error = %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no call since the flag was explicitly set on the command line, got %d total calls", calls)
+	}
+}
+
+func TestWatchSignalTriggersReParse(t *testing.T) {
+	var host string
+
+	t.Setenv("HOST", "before.example.com")
+
+	f := NewFlagSet("test", ExitOnError)
+	f.Var(&host, "host", "default", "Host usage", "")
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	changed := make(chan struct{}, 1)
+	f.OnChange("host", func(o, n any) {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+
+	stop := f.WatchSignal(syscall.SIGUSR1)
+	defer stop()
+
+	t.Setenv("HOST", "after.example.com")
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("Kill() error = %v", err)
+	}
+
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for WatchSignal to trigger ReParse")
+	}
+
+	f.RLock()
+	got := host
+	f.RUnlock()
+	if got != "after.example.com" {
+		t.Errorf("expected host to be 'after.example.com', but got %q", got)
+	}
+}
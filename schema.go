@@ -0,0 +1,183 @@
+package eflag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// flagType returns a short, human-readable type name for f, and whether it
+// holds a list value, for use by PrintEnvDefaults and DumpSchema.
+func flagType(f *Flag) (typ string, isList bool) {
+	switch f.p.(type) {
+	case *bool:
+		return "bool", false
+	case *time.Duration:
+		return "duration", false
+	case *float64:
+		return "float64", false
+	case *int:
+		return "int", false
+	case *int64:
+		return "int64", false
+	case *string:
+		return "string", false
+	case *uint:
+		return "uint", false
+	case *uint64:
+		return "uint64", false
+	case *StringList:
+		return "stringlist", true
+	case *IntList:
+		return "intlist", true
+	case *Float64List:
+		return "float64list", true
+	case *DurationList:
+		return "durationlist", true
+	case *StringMap:
+		return "stringmap", false
+	case *EnumString:
+		return "enum", false
+	default:
+		return fmt.Sprintf("%T", f.p), false
+	}
+}
+
+// sortedFlags returns fs's flags sorted by name, for stable, diffable
+// output from PrintEnvDefaults and DumpSchema.
+func (fs *FlagSet) sortedFlags() []*Flag {
+	names := make([]string, 0, len(fs.formal))
+	for name := range fs.formal {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	flags := make([]*Flag, len(names))
+	for i, name := range names {
+		flags[i] = fs.formal[name]
+	}
+	return flags
+}
+
+// PrintEnvDefaults writes a dotenv-style document to w describing every
+// registered flag: a comment line with its usage text, followed by its
+// resolved environment variable name, including any prefix set with
+// SetPrefix, and its default value. Flags registered with env "-" are
+// skipped, since they have no associated environment variable. A flag with
+// more than one fallback env var (see FlagSet.Var) gets one line per env
+// var, in fallback order.
+//
+// The output is suitable as a starting point for a ".env.example" file.
+func (fs *FlagSet) PrintEnvDefaults(w io.Writer) {
+	for _, f := range fs.sortedFlags() {
+		if len(f.Envs) == 0 {
+			continue
+		}
+		if f.Usage != "" {
+			fmt.Fprintf(w, "# %s\n", f.Usage)
+		}
+		for _, env := range f.Envs {
+			fmt.Fprintf(w, "%s=%s\n", resolveEnvName(env, fs.prefix), f.DefValue)
+		}
+	}
+}
+
+// PrintEnvDefaults writes a dotenv-style document describing every flag
+// registered on CommandLine to os.Stdout. See FlagSet.PrintEnvDefaults.
+func PrintEnvDefaults() {
+	CommandLine.PrintEnvDefaults(os.Stdout)
+}
+
+// flagSchema is the per-flag record emitted by FlagSet.DumpSchema.
+type flagSchema struct {
+	Name    string   `json:"name" yaml:"name"`
+	Envs    []string `json:"envs,omitempty" yaml:"envs,omitempty"`
+	Type    string   `json:"type" yaml:"type"`
+	Default string   `json:"default" yaml:"default"`
+	Usage   string   `json:"usage,omitempty" yaml:"usage,omitempty"`
+	List    bool     `json:"list" yaml:"list"`
+}
+
+// DumpSchema writes a document to w describing every flag registered on
+// fs: its name, resolved environment variable(s), type, default value,
+// usage text, and whether it holds a list value. format must be "json" or
+// "yaml"; any other value returns an error. This is useful for generating
+// deployment manifests and Helm values docs from a single source of truth.
+func (fs *FlagSet) DumpSchema(w io.Writer, format string) error {
+	flags := fs.sortedFlags()
+	schemas := make([]flagSchema, len(flags))
+	for i, f := range flags {
+		typ, isList := flagType(f)
+
+		var envs []string
+		for _, env := range f.Envs {
+			envs = append(envs, resolveEnvName(env, fs.prefix))
+		}
+
+		schemas[i] = flagSchema{
+			Name:    f.Name,
+			Envs:    envs,
+			Type:    typ,
+			Default: f.DefValue,
+			Usage:   f.Usage,
+			List:    isList,
+		}
+	}
+
+	switch format {
+	case "json":
+		b, err := json.MarshalIndent(schemas, "", "  ")
+		if err != nil {
+			return err
+		}
+		b = append(b, '\n')
+		_, err = w.Write(b)
+		return err
+	case "yaml":
+		return writeSchemaYAML(w, schemas)
+	default:
+		return fmt.Errorf("eflag: unsupported schema format %q", format)
+	}
+}
+
+// DumpSchema writes a document describing every flag registered on
+// CommandLine to w. See FlagSet.DumpSchema.
+func DumpSchema(w io.Writer, format string) error {
+	return CommandLine.DumpSchema(w, format)
+}
+
+// writeSchemaYAML writes schemas to w as a YAML sequence of mappings. The
+// output uses "- " sequence items, which parseYAML does not read.
+func writeSchemaYAML(w io.Writer, schemas []flagSchema) error {
+	for _, s := range schemas {
+		fmt.Fprintf(w, "- name: %s\n", s.Name)
+		if len(s.Envs) > 0 {
+			fmt.Fprintln(w, "  envs:")
+			for _, env := range s.Envs {
+				fmt.Fprintf(w, "    - %s\n", env)
+			}
+		}
+		fmt.Fprintf(w, "  type: %s\n", s.Type)
+		fmt.Fprintf(w, "  default: %s\n", yamlScalar(s.Default))
+		if s.Usage != "" {
+			fmt.Fprintf(w, "  usage: %s\n", yamlScalar(s.Usage))
+		}
+		fmt.Fprintf(w, "  list: %t\n", s.List)
+	}
+	return nil
+}
+
+// yamlScalar renders s as a YAML scalar, quoting it when it's empty or
+// contains characters ("#", ":", quotes) that would otherwise change its
+// meaning.
+func yamlScalar(s string) string {
+	if s == "" || strings.ContainsAny(s, ":#'\"") {
+		return strconv.Quote(s)
+	}
+	return s
+}
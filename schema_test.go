@@ -0,0 +1,137 @@
+package eflag
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPrintEnvDefaults(t *testing.T) {
+	var host string
+	var port int
+	var secret string
+	var tags StringList
+
+	f := NewFlagSet("test", ExitOnError)
+	f.SetPrefix("APP")
+	f.Var(&host, "host", "localhost", "Host to listen on", "")
+	f.Var(&port, "port", 8080, "Port to listen on", "PORT,LEGACY_PORT")
+	f.Var(&secret, "secret", "", "Not environment-configurable", "-")
+	f.Var(&tags, "tags", "a,b", "Comma-separated tags", "")
+
+	var buf bytes.Buffer
+	f.PrintEnvDefaults(&buf)
+	out := buf.String()
+
+	if strings.Contains(out, "SECRET") {
+		t.Errorf("expected no output for env \"-\" flag, got:\n%s", out)
+	}
+
+	for _, want := range []string{
+		"# Host to listen on",
+		"APP_HOST=localhost",
+		"# Port to listen on",
+		"APP_PORT=8080",
+		"APP_LEGACY_PORT=8080",
+		"APP_TAGS=a,b",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestDumpSchemaJSON(t *testing.T) {
+	var host string
+	var ports IntList
+
+	f := NewFlagSet("test", ExitOnError)
+	f.Var(&host, "host", "localhost", "Host to listen on", "HOST")
+	f.Var(&ports, "ports", "8080,8443", "Ports to listen on", "")
+
+	var buf bytes.Buffer
+	if err := f.DumpSchema(&buf, "json"); err != nil {
+		t.Fatalf("DumpSchema() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`"name": "host"`,
+		`"type": "string"`,
+		`"default": "localhost"`,
+		`"name": "ports"`,
+		`"type": "intlist"`,
+		`"list": true`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected JSON schema to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestDumpSchemaYAML(t *testing.T) {
+	var host string
+
+	f := NewFlagSet("test", ExitOnError)
+	f.Var(&host, "host", "localhost", "Host to listen on", "HOST")
+
+	var buf bytes.Buffer
+	if err := f.DumpSchema(&buf, "yaml"); err != nil {
+		t.Fatalf("DumpSchema() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"- name: host",
+		"  envs:",
+		"    - HOST",
+		"  type: string",
+		"  default: localhost",
+		"  list: false",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected YAML schema to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestDumpSchemaUnsupportedFormat(t *testing.T) {
+	f := NewFlagSet("test", ExitOnError)
+	var buf bytes.Buffer
+	if err := f.DumpSchema(&buf, "xml"); err == nil {
+		t.Error("DumpSchema() expected error for unsupported format, got nil")
+	}
+}
+
+// TestPrintEnvDefaultsConcurrentWithReParse exercises PrintEnvDefaults and
+// DumpSchema concurrently with ReParse (as WatchSignal would trigger it),
+// to guard against a data race on a flag's Envs slice. Run with -race.
+func TestPrintEnvDefaultsConcurrentWithReParse(t *testing.T) {
+	var host string
+
+	f := NewFlagSet("test", ExitOnError)
+	f.SetPrefix("APP")
+	f.Var(&host, "host", "localhost", "Host to listen on", "HOST,LEGACY_HOST")
+
+	os.Setenv("HOST", "reloaded.example.com")
+	defer os.Unsetenv("HOST")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			if err := f.ReParse(); err != nil {
+				t.Errorf("ReParse() error = %v", err)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		var buf bytes.Buffer
+		f.PrintEnvDefaults(&buf)
+		if err := f.DumpSchema(&buf, "json"); err != nil {
+			t.Errorf("DumpSchema() error = %v", err)
+		}
+	}
+	<-done
+}
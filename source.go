@@ -0,0 +1,282 @@
+package eflag
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// InputSource is a read-only provider of configuration values, keyed by a
+// dotted path (e.g. "db.host"). Implementations back FlagSet.RegisterSource
+// and are consulted by parse() after CLI flags and environment variables,
+// in registration order.
+type InputSource interface {
+	// String returns the string value for name and whether it was found.
+	String(name string) (string, bool)
+	// StringSlice returns the value for name split into a list, for flags
+	// such as StringList, and whether it was found.
+	StringSlice(name string) ([]string, bool)
+}
+
+// mapSource is an InputSource backed by a parsed, possibly nested,
+// map[string]interface{} tree. name is resolved by splitting on "." and
+// descending into nested maps, so it powers the YAML, JSON, and TOML
+// sources, which all support dotted key paths.
+type mapSource struct {
+	data map[string]interface{}
+}
+
+func (s *mapSource) lookup(name string) (interface{}, bool) {
+	var cur interface{} = s.data
+	for _, part := range strings.Split(name, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		cur, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func (s *mapSource) String(name string) (string, bool) {
+	v, ok := s.lookup(name)
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("%v", v), true
+}
+
+func (s *mapSource) StringSlice(name string) ([]string, bool) {
+	v, ok := s.lookup(name)
+	if !ok {
+		return nil, false
+	}
+	switch vv := v.(type) {
+	case []interface{}:
+		out := make([]string, len(vv))
+		for i, e := range vv {
+			out[i] = fmt.Sprintf("%v", e)
+		}
+		return out, true
+	case string:
+		return SplitWithComma(vv), true
+	case map[string]interface{}:
+		// An object becomes sorted "key=value" pairs, for StringMap.
+		keys := make([]string, 0, len(vv))
+		for k := range vv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := make([]string, len(keys))
+		for i, k := range keys {
+			out[i] = fmt.Sprintf("%s=%v", k, vv[k])
+		}
+		return out, true
+	default:
+		return []string{fmt.Sprintf("%v", vv)}, true
+	}
+}
+
+// NewJSONSource returns an InputSource that reads configuration from the
+// JSON file at path. Nested objects are addressable via dotted key paths,
+// e.g. {"db": {"host": "..."}} is reachable as "db.host".
+func NewJSONSource(path string) (InputSource, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]interface{})
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, fmt.Errorf("eflag: parse JSON source %s: %w", path, err)
+	}
+	return &mapSource{data: data}, nil
+}
+
+// NewYAMLSource returns an InputSource that reads configuration from the
+// YAML file at path. It supports the common subset of YAML used for flat
+// and nested configuration: "key: value" pairs with two-space indentation
+// for nesting. Nested keys are addressable via dotted key paths, e.g.
+//
+//	db:
+//	  host: localhost
+//
+// is reachable as "db.host".
+func NewYAMLSource(path string) (InputSource, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := parseYAML(b)
+	if err != nil {
+		return nil, fmt.Errorf("eflag: parse YAML source %s: %w", path, err)
+	}
+	return &mapSource{data: data}, nil
+}
+
+// NewTOMLSource returns an InputSource that reads configuration from the
+// TOML file at path. It supports top-level "key = value" pairs and
+// "[section]" tables; keys inside a table are addressable via dotted key
+// paths, e.g. [db]\nhost = "localhost" is reachable as "db.host".
+func NewTOMLSource(path string) (InputSource, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := parseTOML(b)
+	if err != nil {
+		return nil, fmt.Errorf("eflag: parse TOML source %s: %w", path, err)
+	}
+	return &mapSource{data: data}, nil
+}
+
+// dotEnvSource is an InputSource backed by a parsed .env file. Keys are
+// flat, as .env files have no nesting concept, so name is matched both
+// verbatim and after converting it to SCREAMING_SNAKE_CASE, mirroring how
+// eflag derives environment variable names from flag names.
+type dotEnvSource struct {
+	data map[string]string
+}
+
+// NewDotEnvSource returns an InputSource that reads KEY=value pairs from
+// the dotenv-formatted file at path. Blank lines and lines starting with
+// "#" are ignored, and values may optionally be wrapped in matching single
+// or double quotes.
+func NewDotEnvSource(path string) (InputSource, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := parseDotEnv(b)
+	if err != nil {
+		return nil, fmt.Errorf("eflag: parse dotenv source %s: %w", path, err)
+	}
+	return &dotEnvSource{data: data}, nil
+}
+
+func (s *dotEnvSource) resolve(name string) (string, bool) {
+	if v, ok := s.data[name]; ok {
+		return v, true
+	}
+	if v, ok := s.data[MixedCapsToScreamingSnake(name)]; ok {
+		return v, true
+	}
+	return "", false
+}
+
+func (s *dotEnvSource) String(name string) (string, bool) {
+	return s.resolve(name)
+}
+
+func (s *dotEnvSource) StringSlice(name string) ([]string, bool) {
+	v, ok := s.resolve(name)
+	if !ok {
+		return nil, false
+	}
+	return SplitWithComma(v), true
+}
+
+// parseYAML parses the flat/two-space-indent subset of YAML described by
+// NewYAMLSource into a nested map.
+func parseYAML(b []byte) (map[string]interface{}, error) {
+	root := make(map[string]interface{})
+	// stack of (indent, map) pairs describing the nesting path we're in.
+	type frame struct {
+		indent int
+		m      map[string]interface{}
+	}
+	stack := []frame{{indent: -1, m: root}}
+
+	for i, rawLine := range strings.Split(string(b), "\n") {
+		line := strings.TrimRight(rawLine, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", i+1)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		for len(stack) > 1 && indent <= stack[len(stack)-1].indent {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1].m
+
+		if value == "" {
+			child := make(map[string]interface{})
+			parent[key] = child
+			stack = append(stack, frame{indent: indent, m: child})
+			continue
+		}
+		parent[key] = unquote(value)
+	}
+	return root, nil
+}
+
+// parseTOML parses the top-level key/value and [section] subset of TOML
+// described by NewTOMLSource into a nested map.
+func parseTOML(b []byte) (map[string]interface{}, error) {
+	root := make(map[string]interface{})
+	cur := root
+
+	for i, rawLine := range strings.Split(string(b), "\n") {
+		line := strings.TrimSpace(strings.TrimRight(rawLine, "\r"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section := strings.TrimSpace(line[1 : len(line)-1])
+			child := make(map[string]interface{})
+			root[section] = child
+			cur = child
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key = value\"", i+1)
+		}
+		cur[strings.TrimSpace(key)] = unquote(strings.TrimSpace(value))
+	}
+	return root, nil
+}
+
+// parseDotEnv parses the KEY=value subset of dotenv files described by
+// NewDotEnvSource into a flat map.
+func parseDotEnv(b []byte) (map[string]string, error) {
+	data := make(map[string]string)
+	for i, rawLine := range strings.Split(string(b), "\n") {
+		line := strings.TrimSpace(strings.TrimRight(rawLine, "\r"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"KEY=value\"", i+1)
+		}
+		data[strings.TrimSpace(key)] = unquote(strings.TrimSpace(value))
+	}
+	return data, nil
+}
+
+// unquote strips a single layer of matching single or double quotes from s.
+func unquote(s string) string {
+	if len(s) >= 2 {
+		if (s[0] == '"' && s[len(s)-1] == '"') || (s[0] == '\'' && s[len(s)-1] == '\'') {
+			return s[1 : len(s)-1]
+		}
+	}
+	return s
+}
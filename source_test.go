@@ -0,0 +1,138 @@
+package eflag
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	return path
+}
+
+func TestYAMLSourcePrecedence(t *testing.T) {
+	path := writeTempFile(t, "config.yaml", "host: yamlhost\ndb:\n  host: dbhost\n  port: 5432\n")
+	src, err := NewYAMLSource(path)
+	if err != nil {
+		t.Fatalf("NewYAMLSource() error = %v", err)
+	}
+
+	var host string
+	var dbHost string
+
+	f := NewFlagSet("test", ExitOnError)
+	f.Var(&host, "host", "default", "host usage", "-")
+	f.Var(&dbHost, "dbhost", "default", "db host usage", "-", WithConfigKey("db.host"))
+	f.RegisterSource(src)
+	f.Parse(nil)
+
+	if host != "yamlhost" {
+		t.Errorf("host = %q, want %q", host, "yamlhost")
+	}
+	if dbHost != "dbhost" {
+		t.Errorf("dbHost = %q, want %q", dbHost, "dbhost")
+	}
+}
+
+func TestJSONSourceStringSlice(t *testing.T) {
+	path := writeTempFile(t, "config.json", `{"tags": ["a", "b", "c"]}`)
+	src, err := NewJSONSource(path)
+	if err != nil {
+		t.Fatalf("NewJSONSource() error = %v", err)
+	}
+
+	var tags StringList
+
+	f := NewFlagSet("test", ExitOnError)
+	f.Var(&tags, "tags", "", "tags usage", "-")
+	f.RegisterSource(src)
+	f.Parse(nil)
+
+	if !reflect.DeepEqual(tags.Value(), []string{"a", "b", "c"}) {
+		t.Errorf("tags.Value() = %v, want %v", tags.Value(), []string{"a", "b", "c"})
+	}
+}
+
+func TestJSONSourceStringMap(t *testing.T) {
+	path := writeTempFile(t, "config.json", `{"labels": ["a=1", "b=2"]}`)
+	src, err := NewJSONSource(path)
+	if err != nil {
+		t.Fatalf("NewJSONSource() error = %v", err)
+	}
+
+	var labels StringMap
+
+	f := NewFlagSet("test", ExitOnError)
+	f.Var(&labels, "labels", "", "labels usage", "-")
+	f.RegisterSource(src)
+	f.Parse(nil)
+
+	want := map[string]string{"a": "1", "b": "2"}
+	if !reflect.DeepEqual(labels.Value(), want) {
+		t.Errorf("labels.Value() = %v, want %v", labels.Value(), want)
+	}
+}
+
+func TestTOMLSourceSection(t *testing.T) {
+	path := writeTempFile(t, "config.toml", "[db]\nhost = \"tomlhost\"\n")
+	src, err := NewTOMLSource(path)
+	if err != nil {
+		t.Fatalf("NewTOMLSource() error = %v", err)
+	}
+
+	var dbHost string
+
+	f := NewFlagSet("test", ExitOnError)
+	f.Var(&dbHost, "dbhost", "default", "db host usage", "-", WithConfigKey("db.host"))
+	f.RegisterSource(src)
+	f.Parse(nil)
+
+	if dbHost != "tomlhost" {
+		t.Errorf("dbHost = %q, want %q", dbHost, "tomlhost")
+	}
+}
+
+func TestDotEnvSourceFallsBackToScreamingSnake(t *testing.T) {
+	path := writeTempFile(t, ".env", "# comment\nMY_STRING=fromdotenv\n")
+	src, err := NewDotEnvSource(path)
+	if err != nil {
+		t.Fatalf("NewDotEnvSource() error = %v", err)
+	}
+
+	var myString string
+
+	f := NewFlagSet("test", ExitOnError)
+	f.Var(&myString, "myString", "default", "usage", "-")
+	f.RegisterSource(src)
+	f.Parse(nil)
+
+	if myString != "fromdotenv" {
+		t.Errorf("myString = %q, want %q", myString, "fromdotenv")
+	}
+}
+
+func TestEnvTakesPrecedenceOverSources(t *testing.T) {
+	path := writeTempFile(t, "config.yaml", "host: yamlhost\n")
+	src, err := NewYAMLSource(path)
+	if err != nil {
+		t.Fatalf("NewYAMLSource() error = %v", err)
+	}
+
+	t.Setenv("HOST", "envhost")
+
+	var host string
+	f := NewFlagSet("test", ExitOnError)
+	f.Var(&host, "host", "default", "host usage", "")
+	f.RegisterSource(src)
+	f.Parse(nil)
+
+	if host != "envhost" {
+		t.Errorf("host = %q, want %q", host, "envhost")
+	}
+}
@@ -1,19 +1,284 @@
 package eflag
 
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // StringList represents a list of strings that can be parsed from a comma-separated string.
 type StringList struct {
 	p     string   // p is for flag set
 	value []string // The list of strings parsed from p, a comma-separated string
 }
 
-// setValue sets the value of the StringList by splitting the p with commas.
+// setValue sets the value of the StringList by splitting the p with
+// commas, or clears it if p is empty, so that an env var or source value
+// explicitly resolving to "" (as opposed to never resolving at all)
+// empties out a previously-populated list instead of leaving it stale.
 func (sl *StringList) setValue() {
-	if sl.p != "" {
-		sl.value = SplitWithComma(sl.p)
+	if sl.p == "" {
+		sl.value = nil
+		return
 	}
+	sl.value = SplitWithComma(sl.p)
 }
 
 // Value returns the current list of strings stored in the StringList.
 func (sl *StringList) Value() []string {
 	return sl.value
 }
+
+// commaSeparated marks StringList as a commaSeparatedValue.
+func (sl *StringList) commaSeparated() {}
+
+// IntList is a flag.Value holding a list of ints parsed from a
+// comma-separated string, e.g. "1,2,3".
+type IntList struct {
+	value []int
+}
+
+// String implements flag.Value.
+func (l *IntList) String() string {
+	if l == nil || len(l.value) == 0 {
+		return ""
+	}
+	parts := make([]string, len(l.value))
+	for i, v := range l.value {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set implements flag.Value.
+func (l *IntList) Set(s string) error {
+	if s == "" {
+		l.value = nil
+		return nil
+	}
+	var value []int
+	for _, p := range SplitWithComma(s) {
+		i, err := strconv.Atoi(p)
+		if err != nil {
+			return fmt.Errorf("invalid int %q: %w", p, err)
+		}
+		value = append(value, i)
+	}
+	l.value = value
+	return nil
+}
+
+// Get implements flag.Getter.
+func (l *IntList) Get() interface{} {
+	return l.Value()
+}
+
+// Value returns the current list of ints.
+func (l *IntList) Value() []int {
+	return l.value
+}
+
+// commaSeparated marks IntList as a commaSeparatedValue.
+func (l *IntList) commaSeparated() {}
+
+// Float64List is a flag.Value holding a list of float64s parsed from a
+// comma-separated string, e.g. "1.5,2,3.25".
+type Float64List struct {
+	value []float64
+}
+
+// String implements flag.Value.
+func (l *Float64List) String() string {
+	if l == nil || len(l.value) == 0 {
+		return ""
+	}
+	parts := make([]string, len(l.value))
+	for i, v := range l.value {
+		parts[i] = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set implements flag.Value.
+func (l *Float64List) Set(s string) error {
+	if s == "" {
+		l.value = nil
+		return nil
+	}
+	var value []float64
+	for _, p := range SplitWithComma(s) {
+		f, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return fmt.Errorf("invalid float64 %q: %w", p, err)
+		}
+		value = append(value, f)
+	}
+	l.value = value
+	return nil
+}
+
+// Get implements flag.Getter.
+func (l *Float64List) Get() interface{} {
+	return l.Value()
+}
+
+// Value returns the current list of float64s.
+func (l *Float64List) Value() []float64 {
+	return l.value
+}
+
+// commaSeparated marks Float64List as a commaSeparatedValue.
+func (l *Float64List) commaSeparated() {}
+
+// DurationList is a flag.Value holding a list of time.Durations parsed from
+// a comma-separated string, e.g. "1s,500ms,2m".
+type DurationList struct {
+	value []time.Duration
+}
+
+// String implements flag.Value.
+func (l *DurationList) String() string {
+	if l == nil || len(l.value) == 0 {
+		return ""
+	}
+	parts := make([]string, len(l.value))
+	for i, v := range l.value {
+		parts[i] = v.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set implements flag.Value.
+func (l *DurationList) Set(s string) error {
+	if s == "" {
+		l.value = nil
+		return nil
+	}
+	var value []time.Duration
+	for _, p := range SplitWithComma(s) {
+		d, err := time.ParseDuration(p)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", p, err)
+		}
+		value = append(value, d)
+	}
+	l.value = value
+	return nil
+}
+
+// Get implements flag.Getter.
+func (l *DurationList) Get() interface{} {
+	return l.Value()
+}
+
+// Value returns the current list of durations.
+func (l *DurationList) Value() []time.Duration {
+	return l.value
+}
+
+// commaSeparated marks DurationList as a commaSeparatedValue.
+func (l *DurationList) commaSeparated() {}
+
+// StringMap is a flag.Value holding a map of strings parsed from a
+// comma-separated list of key=value pairs, e.g. "a=1,b=2".
+type StringMap struct {
+	value map[string]string
+}
+
+// String implements flag.Value.
+func (m *StringMap) String() string {
+	if m == nil || len(m.value) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(m.value))
+	for k := range m.value {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + m.value[k]
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set implements flag.Value.
+func (m *StringMap) Set(s string) error {
+	if s == "" {
+		m.value = nil
+		return nil
+	}
+	value := make(map[string]string)
+	for _, p := range SplitWithComma(s) {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok {
+			return fmt.Errorf("invalid key=value pair %q", p)
+		}
+		value[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	m.value = value
+	return nil
+}
+
+// Get implements flag.Getter.
+func (m *StringMap) Get() interface{} {
+	return m.Value()
+}
+
+// Value returns the current map of strings.
+func (m *StringMap) Value() map[string]string {
+	return m.value
+}
+
+// commaSeparated marks StringMap as a commaSeparatedValue.
+func (m *StringMap) commaSeparated() {}
+
+// EnumString is a flag.Value that only accepts one of a fixed set of
+// allowed string values.
+type EnumString struct {
+	value   string
+	allowed []string
+}
+
+// NewEnumString returns an EnumString restricted to allowed, initialized to
+// def. def must be one of allowed, or NewEnumString panics, since this
+// indicates a programming error rather than bad user input.
+func NewEnumString(allowed []string, def string) *EnumString {
+	e := &EnumString{allowed: allowed}
+	if err := e.Set(def); err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// String implements flag.Value.
+func (e *EnumString) String() string {
+	if e == nil {
+		return ""
+	}
+	return e.value
+}
+
+// Set implements flag.Value.
+func (e *EnumString) Set(s string) error {
+	for _, a := range e.allowed {
+		if s == a {
+			e.value = s
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid value %q: must be one of %s", s, strings.Join(e.allowed, ", "))
+}
+
+// Get implements flag.Getter.
+func (e *EnumString) Get() interface{} {
+	return e.Value()
+}
+
+// Value returns the current enum value.
+func (e *EnumString) Value() string {
+	return e.value
+}
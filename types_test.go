@@ -0,0 +1,136 @@
+package eflag
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestIntList tests Set and Value for IntList.
+func TestIntList(t *testing.T) {
+	var l IntList
+	if err := l.Set("1, 2,3"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if !reflect.DeepEqual(l.Value(), []int{1, 2, 3}) {
+		t.Errorf("Value() = %v; want %v", l.Value(), []int{1, 2, 3})
+	}
+	if err := l.Set("not-an-int"); err == nil {
+		t.Error("Set() expected error for invalid int, got nil")
+	}
+	if err := l.Set(""); err != nil {
+		t.Fatalf("Set(\"\") error = %v", err)
+	}
+	if l.Value() != nil {
+		t.Errorf("Value() after Set(\"\") = %v; want nil", l.Value())
+	}
+}
+
+// TestFloat64List tests Set and Value for Float64List.
+func TestFloat64List(t *testing.T) {
+	var l Float64List
+	if err := l.Set("1.5, 2, 3.25"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if !reflect.DeepEqual(l.Value(), []float64{1.5, 2, 3.25}) {
+		t.Errorf("Value() = %v; want %v", l.Value(), []float64{1.5, 2, 3.25})
+	}
+	if err := l.Set("not-a-float"); err == nil {
+		t.Error("Set() expected error for invalid float64, got nil")
+	}
+	if err := l.Set(""); err != nil {
+		t.Fatalf("Set(\"\") error = %v", err)
+	}
+	if l.Value() != nil {
+		t.Errorf("Value() after Set(\"\") = %v; want nil", l.Value())
+	}
+}
+
+// TestDurationList tests Set and Value for DurationList.
+func TestDurationList(t *testing.T) {
+	var l DurationList
+	if err := l.Set("1s, 500ms"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	want := []time.Duration{time.Second, 500 * time.Millisecond}
+	if !reflect.DeepEqual(l.Value(), want) {
+		t.Errorf("Value() = %v; want %v", l.Value(), want)
+	}
+	if err := l.Set("not-a-duration"); err == nil {
+		t.Error("Set() expected error for invalid duration, got nil")
+	}
+	if err := l.Set(""); err != nil {
+		t.Fatalf("Set(\"\") error = %v", err)
+	}
+	if l.Value() != nil {
+		t.Errorf("Value() after Set(\"\") = %v; want nil", l.Value())
+	}
+}
+
+// TestStringMap tests Set and Value for StringMap.
+func TestStringMap(t *testing.T) {
+	var m StringMap
+	if err := m.Set("a=1, b=2"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	want := map[string]string{"a": "1", "b": "2"}
+	if !reflect.DeepEqual(m.Value(), want) {
+		t.Errorf("Value() = %v; want %v", m.Value(), want)
+	}
+	if err := m.Set("invalid"); err == nil {
+		t.Error("Set() expected error for missing '=', got nil")
+	}
+	if err := m.Set(""); err != nil {
+		t.Fatalf("Set(\"\") error = %v", err)
+	}
+	if m.Value() != nil {
+		t.Errorf("Value() after Set(\"\") = %v; want nil", m.Value())
+	}
+}
+
+// TestStringMapStringIsSorted tests that String() renders keys in a
+// stable, sorted order regardless of map iteration order, since it feeds
+// Flag.DefValue and therefore the diffable output of PrintEnvDefaults and
+// DumpSchema.
+func TestStringMapStringIsSorted(t *testing.T) {
+	var m StringMap
+	if err := m.Set("e=5,d=4,c=3,b=2,a=1"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	want := "a=1,b=2,c=3,d=4,e=5"
+	for i := 0; i < 20; i++ {
+		if got := m.String(); got != want {
+			t.Fatalf("String() = %q; want %q", got, want)
+		}
+	}
+}
+
+// TestEnumString tests NewEnumString and Set for EnumString.
+func TestEnumString(t *testing.T) {
+	e := NewEnumString([]string{"dev", "staging", "prod"}, "dev")
+	if e.Value() != "dev" {
+		t.Errorf("Value() = %q; want %q", e.Value(), "dev")
+	}
+
+	if err := e.Set("prod"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if e.Value() != "prod" {
+		t.Errorf("Value() = %q; want %q", e.Value(), "prod")
+	}
+
+	if err := e.Set("bogus"); err == nil {
+		t.Error("Set() expected error for disallowed value, got nil")
+	}
+}
+
+// TestNewEnumStringPanicsOnInvalidDefault tests that NewEnumString panics
+// when given a default outside the allowed set.
+func TestNewEnumStringPanicsOnInvalidDefault(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewEnumString() expected panic for invalid default, got none")
+		}
+	}()
+	NewEnumString([]string{"dev", "prod"}, "bogus")
+}
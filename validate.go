@@ -0,0 +1,134 @@
+package eflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Required marks names as required: Parse and ReParse fail unless each one
+// was explicitly set, either on the command line, via an environment
+// variable, or by a registered InputSource (see Flag.IsSet).
+func (fs *FlagSet) Required(names ...string) {
+	fs.required = append(fs.required, names...)
+}
+
+// MutuallyExclusive registers groups of flag names where, within each
+// group, at most one flag may be explicitly set. Parse and ReParse fail if
+// more than one flag in the same group was set.
+func (fs *FlagSet) MutuallyExclusive(groups ...[]string) {
+	fs.exclusiveGroups = append(fs.exclusiveGroups, groups...)
+}
+
+// Required marks names as required on CommandLine. See FlagSet.Required.
+func Required(names ...string) {
+	CommandLine.Required(names...)
+}
+
+// MutuallyExclusive registers groups of flag names on CommandLine. See
+// FlagSet.MutuallyExclusive.
+func MutuallyExclusive(groups ...[]string) {
+	CommandLine.MutuallyExclusive(groups...)
+}
+
+// validate checks required flags, runs per-flag validators registered with
+// WithValidator, and checks mutually-exclusive groups, collecting every
+// violation into a single error. A non-nil error is handled according to
+// fs.errorHandling.
+//
+// It runs after parse() has released fs.mu, so it takes its own read lock
+// around the flags it inspects: Flag.IsSet and Flag.currentValue read
+// state (f.Changed, and for list/map types the underlying slice or map)
+// that a concurrent Parse/ReParse can be writing.
+func (fs *FlagSet) validate() error {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	var errs []string
+
+	for _, name := range fs.required {
+		f := fs.formal[name]
+		if f != nil && f.IsSet() {
+			continue
+		}
+		errs = append(errs, fmt.Sprintf("flag %q is required", name))
+	}
+
+	for _, f := range fs.sortedFlags() {
+		if f.validate == nil {
+			continue
+		}
+		if err := f.validate(f.currentValue()); err != nil {
+			errs = append(errs, fmt.Sprintf("flag %q is invalid: %v", f.Name, err))
+		}
+	}
+
+	for _, group := range fs.exclusiveGroups {
+		var set []string
+		for _, name := range group {
+			if f := fs.formal[name]; f != nil && f.IsSet() {
+				set = append(set, name)
+			}
+		}
+		if len(set) > 1 {
+			errs = append(errs, fmt.Sprintf("flags %s are mutually exclusive", strings.Join(set, ", ")))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return fs.handleError(errors.New(strings.Join(errs, "; ")))
+}
+
+// handleError reports err according to fs.errorHandling: returned as-is
+// for ContinueOnError, printed followed by os.Exit(2) for ExitOnError, or
+// passed to panic for PanicOnError.
+func (fs *FlagSet) handleError(err error) error {
+	switch fs.errorHandling {
+	case ExitOnError:
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	case PanicOnError:
+		panic(err)
+	}
+	return err
+}
+
+// currentValue returns f's current resolved value, for use by validators
+// registered with WithValidator: the dereferenced value for concrete
+// types, the return of Value()/Get() for list, map, and generic
+// flag.Value types that implement flag.Getter, or f.p itself for a
+// flag.Value that does not. Like Flag.IsSet, it reads state that parse() writes
+// under fs.mu, so callers must hold at least fs.mu.RLock(); validate() does
+// this on currentValue's behalf.
+func (f *Flag) currentValue() any {
+	switch v := f.p.(type) {
+	case *bool:
+		return *v
+	case *time.Duration:
+		return *v
+	case *float64:
+		return *v
+	case *int:
+		return *v
+	case *int64:
+		return *v
+	case *string:
+		return *v
+	case *uint:
+		return *v
+	case *uint64:
+		return *v
+	case *StringList:
+		return v.Value()
+	default:
+		if g, ok := f.p.(flag.Getter); ok {
+			return g.Get()
+		}
+		return f.p
+	}
+}
@@ -0,0 +1,132 @@
+package eflag
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRequiredFlagMissing(t *testing.T) {
+	var host string
+
+	f := NewFlagSet("test", ContinueOnError)
+	f.Var(&host, "host", "", "Host usage", "-")
+	f.Required("host")
+
+	err := f.Parse(nil)
+	if err == nil {
+		t.Fatal("Parse() expected error for missing required flag, got nil")
+	}
+	if !strings.Contains(err.Error(), `"host" is required`) {
+		t.Errorf("Parse() error = %v; want mention of missing host flag", err)
+	}
+}
+
+func TestRequiredFlagSetByEnv(t *testing.T) {
+	var host string
+
+	t.Setenv("HOST", "example.com")
+
+	f := NewFlagSet("test", ContinueOnError)
+	f.Var(&host, "host", "", "Host usage", "")
+	f.Required("host")
+
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+}
+
+// TestValidateConcurrentReParse exercises validate() racing parse() under
+// the race detector: two goroutines calling ReParse concurrently must not
+// trip a data race between parse()'s write to f.Changed and validate()'s
+// read of it via Flag.IsSet, even with no validator registered.
+func TestValidateConcurrentReParse(t *testing.T) {
+	var host string
+
+	t.Setenv("HOST", "example.com")
+
+	f := NewFlagSet("test", ContinueOnError)
+	f.Var(&host, "host", "", "Host usage", "")
+	f.Required("host")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f.ReParse()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestWithValidator(t *testing.T) {
+	var port int
+
+	f := NewFlagSet("test", ContinueOnError)
+	f.Var(&port, "port", 0, "Port usage", "-", WithValidator(func(v any) error {
+		if v.(int) <= 0 {
+			return fmt.Errorf("must be positive")
+		}
+		return nil
+	}))
+
+	err := f.Parse([]string{"-port", "-1"})
+	if err == nil {
+		t.Fatal("Parse() expected error for invalid port, got nil")
+	}
+	if !strings.Contains(err.Error(), `"port" is invalid`) {
+		t.Errorf("Parse() error = %v; want mention of invalid port flag", err)
+	}
+
+	if err := f.Parse([]string{"-port", "8080"}); err != nil {
+		t.Errorf("Parse() error = %v; want nil for valid port", err)
+	}
+}
+
+func TestMutuallyExclusive(t *testing.T) {
+	var a, b string
+
+	f := NewFlagSet("test", ContinueOnError)
+	f.Var(&a, "a", "", "a usage", "-")
+	f.Var(&b, "b", "", "b usage", "-")
+	f.MutuallyExclusive([]string{"a", "b"})
+
+	err := f.Parse([]string{"-a", "1", "-b", "2"})
+	if err == nil {
+		t.Fatal("Parse() expected error for mutually exclusive flags, got nil")
+	}
+	if !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Errorf("Parse() error = %v; want mention of mutually exclusive flags", err)
+	}
+
+	f2 := NewFlagSet("test", ContinueOnError)
+	f2.Var(&a, "a", "", "a usage", "-")
+	f2.Var(&b, "b", "", "b usage", "-")
+	f2.MutuallyExclusive([]string{"a", "b"})
+
+	if err := f2.Parse([]string{"-a", "1"}); err != nil {
+		t.Errorf("Parse() error = %v; want nil when only one flag is set", err)
+	}
+}
+
+func TestValidateCollectsAllViolations(t *testing.T) {
+	var required string
+	var invalid int
+
+	f := NewFlagSet("test", ContinueOnError)
+	f.Var(&required, "required", "", "required usage", "-")
+	f.Var(&invalid, "invalid", 0, "invalid usage", "-", WithValidator(func(v any) error {
+		return fmt.Errorf("always invalid")
+	}))
+	f.Required("required")
+
+	err := f.Parse(nil)
+	if err == nil {
+		t.Fatal("Parse() expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), `"required" is required`) || !strings.Contains(err.Error(), `"invalid" is invalid`) {
+		t.Errorf("Parse() error = %v; want both violations reported", err)
+	}
+}